@@ -0,0 +1,121 @@
+package view
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// CyclePolicy controls how a Mapper renders a pointer, map, or slice it
+// revisits on the current path through the value being rendered, i.e. a
+// cycle in the object graph (a linked list node whose Next eventually
+// points back to itself, two structs holding pointers to each other, and so
+// on).
+type CyclePolicy int
+
+const (
+	// CycleNil renders a revisited pointer, map, or slice as nil. It is the
+	// default.
+	CycleNil CyclePolicy = iota
+	// CycleError aborts rendering with a *CycleDetectedError.
+	CycleError
+	// CycleRef renders a revisited pointer, map, or slice as
+	// map[string]interface{}{"$ref": id}, where id is derived from the
+	// value's runtime pointer and type, so repeated references to the same
+	// node share the same id, JSON-Reference style.
+	CycleRef
+)
+
+// OnCycle sets the Mapper's CyclePolicy. The default is CycleNil.
+func OnCycle(p CyclePolicy) MapperOption {
+	return func(m *Mapper) { m.onCycle = p }
+}
+
+// MaxDepth caps how many Ptr/Map/Slice levels deep a Mapper will descend
+// before giving up and rendering nil in their place, guarding against
+// unexpectedly deep object graphs regardless of CyclePolicy. n <= 0 means
+// unlimited, the default.
+func MaxDepth(n int) MapperOption {
+	return func(m *Mapper) { m.maxDepth = n }
+}
+
+// A CycleDetectedError is returned by a Mapper configured with
+// OnCycle(CycleError) when rendering revisits a pointer, map, or slice
+// already on the current path.
+type CycleDetectedError struct {
+	Type reflect.Type
+}
+
+func (e *CycleDetectedError) Error() string {
+	return "struct-view: cycle detected rendering " + e.Type.String()
+}
+
+// visitKey identifies a Ptr, Map, or Slice value by its runtime pointer and
+// type, which is enough to recognize that two reflect.Values seen at
+// different points in a render share the same underlying data.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// renderState is per-Render(WithFilter)-call state: the set of Ptr/Map/Slice
+// values currently being rendered on the path from the root, used to detect
+// cycles, plus the current nesting depth, used to enforce MaxDepth. It is
+// built fresh by mapInterface for every call and threaded through every
+// mapperFunc alongside the value being rendered; it must never be captured
+// by a mapperFunc itself, since those are cached and reused across calls.
+type renderState struct {
+	policy   CyclePolicy
+	maxDepth int
+	depth    int
+	visiting map[visitKey]bool
+}
+
+func newRenderState(m *Mapper) *renderState {
+	return &renderState{policy: m.onCycle, maxDepth: m.maxDepth}
+}
+
+// enter records that v (a Ptr, Map, or Slice value) is about to be rendered.
+// ok is false if doing so would exceed MaxDepth or revisit a value already
+// on the current path; replacement then holds whatever should be rendered
+// in v's place instead. leave must be deferred by the caller to pop v back
+// off the path once it's done rendering, regardless of ok.
+func (rs *renderState) enter(v reflect.Value) (replacement interface{}, leave func(), ok bool) {
+	if rs.maxDepth > 0 && rs.depth >= rs.maxDepth {
+		return nil, func() {}, false
+	}
+	rs.depth++
+	leave = func() { rs.depth-- }
+
+	ptr := v.Pointer()
+	if ptr == 0 {
+		return nil, leave, true
+	}
+
+	key := visitKey{ptr, v.Type()}
+	if rs.visiting[key] {
+		switch rs.policy {
+		case CycleError:
+			panic(renderError{&CycleDetectedError{v.Type()}})
+		case CycleRef:
+			return map[string]interface{}{"$ref": refID(key)}, leave, false
+		default:
+			return nil, leave, false
+		}
+	}
+
+	if rs.visiting == nil {
+		rs.visiting = make(map[visitKey]bool)
+	}
+	rs.visiting[key] = true
+	popVisiting := leave
+	leave = func() {
+		delete(rs.visiting, key)
+		popVisiting()
+	}
+	return nil, leave, true
+}
+
+// refID stably identifies key for CycleRef output across a single render.
+func refID(key visitKey) string {
+	return fmt.Sprintf("%s@%x", key.typ.String(), key.ptr)
+}
@@ -0,0 +1,79 @@
+package view
+
+import (
+	"fmt"
+	"reflect"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+type Node struct {
+	Value int   `view:"admin"`
+	Next  *Node `view:"admin"`
+}
+
+type Wrapper struct {
+	Inner *Wrapper `view:"admin"`
+}
+
+func TestCycles(test *testing.T) {
+	Convey("By default, a revisited pointer renders as nil", test, func() {
+		a := &Node{Value: 1}
+		a.Next = a
+
+		v, err := Render(a, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{"Value": 1, "Next": nil})
+	})
+
+	Convey("OnCycle(CycleError) surfaces a CycleDetectedError", test, func() {
+		a := &Node{Value: 1}
+		a.Next = a
+
+		m := NewMapper(tagName, nil, OnCycle(CycleError))
+		v, err := m.Render(a, "admin")
+		So(v, ShouldBeNil)
+		So(err, ShouldHaveSameTypeAs, &CycleDetectedError{})
+	})
+
+	Convey("OnCycle(CycleRef) renders a revisited pointer as a stable $ref", test, func() {
+		a := &Node{Value: 1}
+		a.Next = a
+
+		m := NewMapper(tagName, nil, OnCycle(CycleRef))
+		v, err := m.Render(a, "admin")
+		So(err, ShouldBeNil)
+
+		ref := fmt.Sprintf("%s@%x", reflect.TypeOf(a).String(), reflect.ValueOf(a).Pointer())
+		So(v, ShouldResemble, map[string]interface{}{
+			"Value": 1,
+			"Next":  map[string]interface{}{"$ref": ref},
+		})
+	})
+
+	Convey("MaxDepth stops descending past the configured number of levels", test, func() {
+		w := &Wrapper{Inner: &Wrapper{Inner: &Wrapper{}}}
+
+		m := NewMapper(tagName, nil, MaxDepth(1))
+		v, err := m.Render(w, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{"Inner": nil})
+	})
+
+	Convey("The same pointer can appear more than once outside of a cycle", test, func() {
+		shared := &Node{Value: 42}
+		type Pair struct {
+			A *Node `view:"admin"`
+			B *Node `view:"admin"`
+		}
+		p := &Pair{A: shared, B: shared}
+
+		v, err := Render(p, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{
+			"A": map[string]interface{}{"Value": 42, "Next": nil},
+			"B": map[string]interface{}{"Value": 42, "Next": nil},
+		})
+	})
+}
@@ -0,0 +1,128 @@
+package view
+
+import "strings"
+
+// FieldFilter decides whether a field reached at fieldPath (its dotted name,
+// split on fieldJoiner) should be included in the rendered output. When the
+// field's own value still needs projecting further down (e.g. it is itself
+// a struct), sub is the FieldFilter to apply to its fields; a nil sub means
+// the field is included in full, with no further filtering below it.
+type FieldFilter interface {
+	Filter(fieldPath []string) (include bool, sub FieldFilter)
+}
+
+// Mask is a FieldFilter that includes only the fields named by paths, each
+// given as a fieldJoiner-separated path from the root, e.g. "User.Id" or
+// "Products.Name".
+type Mask struct {
+	children map[string]*Mask
+	leaf     bool
+}
+
+// NewMask builds a Mask from a set of dotted field paths.
+func NewMask(paths ...string) *Mask {
+	m := &Mask{}
+	for _, p := range paths {
+		m.add(strings.Split(p, fieldJoiner))
+	}
+	return m
+}
+
+func (m *Mask) add(segments []string) {
+	if len(segments) == 0 {
+		m.leaf = true
+		return
+	}
+	if m.children == nil {
+		m.children = make(map[string]*Mask)
+	}
+	child, ok := m.children[segments[0]]
+	if !ok {
+		child = &Mask{}
+		m.children[segments[0]] = child
+	}
+	child.add(segments[1:])
+}
+
+func (m *Mask) Filter(fieldPath []string) (include bool, sub FieldFilter) {
+	node := m
+	for _, seg := range fieldPath {
+		if node.leaf {
+			return true, nil
+		}
+		child, ok := node.children[seg]
+		if !ok {
+			return false, nil
+		}
+		node = child
+	}
+	if node.leaf || len(node.children) == 0 {
+		return true, nil
+	}
+	return true, node
+}
+
+// Inverse wraps a FieldFilter and includes exactly what the wrapped filter
+// would exclude, and vice versa.
+type Inverse struct {
+	FieldFilter
+}
+
+func (inv Inverse) Filter(fieldPath []string) (include bool, sub FieldFilter) {
+	include, sub = inv.FieldFilter.Filter(fieldPath)
+	if sub == nil {
+		return !include, nil
+	}
+	return true, Inverse{sub}
+}
+
+// Union is a FieldFilter that includes a field as soon as any one of its
+// FieldFilters would include it.
+type Union []FieldFilter
+
+func (u Union) Filter(fieldPath []string) (include bool, sub FieldFilter) {
+	var subs Union
+	for _, f := range u {
+		inc, s := f.Filter(fieldPath)
+		if !inc {
+			continue
+		}
+		if s == nil {
+			return true, nil
+		}
+		subs = append(subs, s)
+	}
+	switch len(subs) {
+	case 0:
+		return false, nil
+	case 1:
+		return true, subs[0]
+	default:
+		return true, subs
+	}
+}
+
+// Intersection is a FieldFilter that includes a field only if all of its
+// FieldFilters would include it.
+type Intersection []FieldFilter
+
+func (in Intersection) Filter(fieldPath []string) (include bool, sub FieldFilter) {
+	var subs Intersection
+	for _, f := range in {
+		inc, s := f.Filter(fieldPath)
+		if !inc {
+			return false, nil
+		}
+		if s != nil {
+			subs = append(subs, s)
+		}
+	}
+	switch len(subs) {
+	case 0:
+		return true, nil
+	case 1:
+		return true, subs[0]
+	default:
+		return true, subs
+	}
+}
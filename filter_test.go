@@ -0,0 +1,84 @@
+package view
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestRenderWithFilter(test *testing.T) {
+	u := &User{
+		Id:       7,
+		Name:     "Jon Doe",
+		Password: "secret",
+		Key:      "12345",
+	}
+	a := &Activity{
+		u, []Product{
+			{3, "T-shirt", "123-456-7890"},
+			{5, "Shoes", "789-000-1111"},
+		},
+	}
+
+	Convey("It projects a struct onto a Mask of dotted field paths", test, func() {
+		mask := NewMask("User.Id", "Products.Name")
+		v, err := RenderWithFilter(a, mask)
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{
+			"User": map[string]interface{}{"Id": uint(7)},
+			"Products": []interface{}{
+				map[string]interface{}{"Name": "T-shirt"},
+				map[string]interface{}{"Name": "Shoes"},
+			},
+		})
+	})
+
+	Convey("Inverse excludes a Mask's fields instead of including them", test, func() {
+		mask := NewMask("Password", "Key")
+		v, err := RenderWithFilter(u, Inverse{mask})
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{
+			"Id":   uint(7),
+			"Name": "Jon Doe",
+		})
+	})
+
+	Convey("Union includes a field that any one FieldFilter would include", test, func() {
+		f := Union{NewMask("Id"), NewMask("Name")}
+		v, err := RenderWithFilter(u, f)
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{
+			"Id":   uint(7),
+			"Name": "Jon Doe",
+		})
+	})
+
+	Convey("Intersection includes a field only if every FieldFilter would include it", test, func() {
+		f := Intersection{NewMask("Id", "Name"), NewMask("Name")}
+		v, err := RenderWithFilter(u, f)
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{
+			"Name": "Jon Doe",
+		})
+	})
+
+	Convey("A fully-included field isn't corrupted by a prior Render(x, \"\") on the same type and Mapper", test, func() {
+		type MixedInner struct {
+			Untagged string
+			Tagged   string `view:"admin"`
+		}
+		type Outer struct {
+			Inner MixedInner
+		}
+		inner := MixedInner{Untagged: "u", Tagged: "t"}
+
+		m := NewMapper(tagName, nil)
+		_, err := m.Render(inner, "")
+		So(err, ShouldBeNil)
+
+		v, err := m.RenderWithFilter(Outer{Inner: inner}, NewMask("Inner"))
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{
+			"Inner": inner,
+		})
+	})
+}
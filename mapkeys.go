@@ -0,0 +1,78 @@
+package view
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// An UnsupportedKeyError is returned by a StringKeys-enabled Mapper when a
+// map key cannot be converted to a string, symmetric with
+// UnsupportedTypeError for values.
+type UnsupportedKeyError struct {
+	Type reflect.Type
+	Err  error
+}
+
+func (e *UnsupportedKeyError) Error() string {
+	return "struct-view: unsupported map key of type " + e.Type.String() + ": " + e.Err.Error()
+}
+
+// KeyFunc renders a map key as a string, for Mappers configured with
+// StringKeys or WithKeyFunc.
+type KeyFunc func(reflect.Value) (string, error)
+
+// defaultKeyFunc is used by StringKeys(true) when no WithKeyFunc override is
+// set: it honors encoding.TextMarshaler, falling back to fmt.Sprint.
+func defaultKeyFunc(v reflect.Value) (string, error) {
+	if v.CanInterface() {
+		if tm, ok := v.Interface().(encoding.TextMarshaler); ok {
+			text, err := tm.MarshalText()
+			if err != nil {
+				return "", err
+			}
+			return string(text), nil
+		}
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// newStringKeyMapMapper builds the mapperFunc for a map rendered under
+// StringKeys: every key is converted to a string via keyFunc, and every
+// value still goes through elemFn (nil meaning no transform is needed).
+func newStringKeyMapMapper(t reflect.Type, opt *options, elemFn mapperFunc) mapperFunc {
+	sm := &stringKeyMapMapper{
+		keyType:    t.Key(),
+		keyFunc:    opt.mapper.resolvedKeyFunc(),
+		elemMapper: elemFn,
+	}
+	return sm.mapValue
+}
+
+type stringKeyMapMapper struct {
+	keyType    reflect.Type
+	keyFunc    KeyFunc
+	elemMapper mapperFunc
+}
+
+func (sm *stringKeyMapMapper) mapValue(v reflect.Value, rs *renderState) interface{} {
+	replacement, leave, ok := rs.enter(v)
+	defer leave()
+	if !ok {
+		return replacement
+	}
+	result := make(map[string]interface{}, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := sm.keyFunc(iter.Key())
+		if err != nil {
+			panic(renderError{&UnsupportedKeyError{sm.keyType, err}})
+		}
+		if sm.elemMapper != nil {
+			result[key] = sm.elemMapper(iter.Value(), rs)
+		} else {
+			result[key] = iter.Value().Interface()
+		}
+	}
+	return result
+}
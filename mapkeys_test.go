@@ -0,0 +1,58 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+type ProductKey struct {
+	Region string
+	SKU    int
+}
+
+func (k ProductKey) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%s/%d", k.Region, k.SKU)), nil
+}
+
+func TestStringKeys(test *testing.T) {
+	Convey("StringKeys converts numeric keys to a map[string]interface{}", test, func() {
+		m := NewMapper(tagName, nil, StringKeys(true))
+		src := map[int]string{1: "one", 2: "two"}
+		v, err := m.Render(src, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{"1": "one", "2": "two"})
+	})
+
+	Convey("StringKeys honors encoding.TextMarshaler on struct keys", test, func() {
+		m := NewMapper(tagName, nil, StringKeys(true))
+		src := map[ProductKey]int{{"US", 42}: 10}
+		v, err := m.Render(src, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{"US/42": 10})
+	})
+
+	Convey("WithKeyFunc overrides the default key stringification", test, func() {
+		m := NewMapper(tagName, nil, WithKeyFunc(func(v reflect.Value) (string, error) {
+			return "k" + fmt.Sprint(v.Interface()), nil
+		}))
+		src := map[int]string{1: "one"}
+		v, err := m.Render(src, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{"k1": "one"})
+	})
+
+	Convey("A failing KeyFunc surfaces an UnsupportedKeyError", test, func() {
+		boom := errors.New("boom")
+		m := NewMapper(tagName, nil, WithKeyFunc(func(v reflect.Value) (string, error) {
+			return "", boom
+		}))
+		src := map[int]string{1: "one"}
+		v, err := m.Render(src, "admin")
+		So(v, ShouldBeNil)
+		So(err, ShouldHaveSameTypeAs, &UnsupportedKeyError{})
+	})
+}
@@ -0,0 +1,148 @@
+package view
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// Viewer lets a type fully control its own rendering: when src (or *src)
+// implements Viewer, Render/RenderWithFilter call ViewRender instead of
+// reflecting into the value.
+type Viewer interface {
+	ViewRender(viewName string) (interface{}, error)
+}
+
+var (
+	viewerType        = reflect.TypeOf((*Viewer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// implementer reports whether t, or a pointer to t, implements iface. When
+// only the pointer does, needsAddr reports that the mapperFunc must take
+// v.Addr() before the type assertion, which requires v to be addressable.
+func implementer(t reflect.Type, iface reflect.Type) (needsAddr bool, ok bool) {
+	if t.Implements(iface) {
+		return false, true
+	}
+	if reflect.PtrTo(t).Implements(iface) {
+		return true, true
+	}
+	return false, false
+}
+
+// addrFallback is the mapperFunc to use when needsAddr is true but the value
+// being rendered turns out to be unaddressable at render time (it came out
+// of a map or an interface, or was passed to Render by value): the
+// pointer-receiver method can't be reached, so rendering falls back to t's
+// Kind-based default, mirroring how encoding/json treats an unaddressable
+// value. Returns nil when needsAddr is false, since no fallback is needed.
+func addrFallback(needsAddr bool, t reflect.Type, opt *options) mapperFunc {
+	if !needsAddr {
+		return nil
+	}
+	fn := newKindMapper(t, opt)
+	if fn == nil {
+		fn = identityMapper
+	}
+	return fn
+}
+
+func newViewerMapper(t reflect.Type, opt *options) mapperFunc {
+	needsAddr, ok := implementer(t, viewerType)
+	if !ok {
+		return nil
+	}
+	vm := &viewerMapper{needsAddr, opt.viewName, addrFallback(needsAddr, t, opt)}
+	return vm.mapValue
+}
+
+type viewerMapper struct {
+	needsAddr bool
+	viewName  string
+	fallback  mapperFunc
+}
+
+func (vm *viewerMapper) mapValue(v reflect.Value, rs *renderState) interface{} {
+	if vm.needsAddr {
+		if !v.CanAddr() {
+			return vm.fallback(v, rs)
+		}
+		v = v.Addr()
+	}
+	out, err := v.Interface().(Viewer).ViewRender(vm.viewName)
+	if err != nil {
+		panic(renderError{err})
+	}
+	return out
+}
+
+// timeMapper renders a time.Time as an RFC3339 string. newTypeMapper checks
+// for time.Time before json.Marshaler and encoding.TextMarshaler, which
+// time.Time also implements and which would render it in RFC3339Nano form.
+func timeMapper(v reflect.Value, rs *renderState) interface{} {
+	return v.Interface().(time.Time).Format(time.RFC3339)
+}
+
+func newTextMarshalerMapper(t reflect.Type, opt *options) mapperFunc {
+	needsAddr, ok := implementer(t, textMarshalerType)
+	if !ok {
+		return nil
+	}
+	tm := &textMarshalerMapper{needsAddr, addrFallback(needsAddr, t, opt)}
+	return tm.mapValue
+}
+
+type textMarshalerMapper struct {
+	needsAddr bool
+	fallback  mapperFunc
+}
+
+func (tm *textMarshalerMapper) mapValue(v reflect.Value, rs *renderState) interface{} {
+	if tm.needsAddr {
+		if !v.CanAddr() {
+			return tm.fallback(v, rs)
+		}
+		v = v.Addr()
+	}
+	text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		panic(renderError{err})
+	}
+	return string(text)
+}
+
+func newJSONMarshalerMapper(t reflect.Type, opt *options) mapperFunc {
+	needsAddr, ok := implementer(t, jsonMarshalerType)
+	if !ok {
+		return nil
+	}
+	jm := &jsonMarshalerMapper{needsAddr, addrFallback(needsAddr, t, opt)}
+	return jm.mapValue
+}
+
+type jsonMarshalerMapper struct {
+	needsAddr bool
+	fallback  mapperFunc
+}
+
+func (jm *jsonMarshalerMapper) mapValue(v reflect.Value, rs *renderState) interface{} {
+	if jm.needsAddr {
+		if !v.CanAddr() {
+			return jm.fallback(v, rs)
+		}
+		v = v.Addr()
+	}
+	data, err := v.Interface().(json.Marshaler).MarshalJSON()
+	if err != nil {
+		panic(renderError{err})
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		panic(renderError{err})
+	}
+	return out
+}
@@ -0,0 +1,154 @@
+package view
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+type Money int // cents
+
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("$%d.%02d", m/100, m%100)), nil
+}
+
+type Tags []string
+
+func (t Tags) MarshalJSON() ([]byte, error) {
+	return []byte(`{"count":` + strconv.Itoa(len(t)) + `}`), nil
+}
+
+// PtrMoney implements encoding.TextMarshaler on a pointer receiver, unlike
+// Money, so that a Render of a value that can't be addressed (e.g. a map
+// value) must fall back instead of panicking on v.Addr().
+type PtrMoney int // cents
+
+func (m *PtrMoney) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("$%d.%02d", *m/100, *m%100)), nil
+}
+
+// DualMarshaler implements both json.Marshaler and encoding.TextMarshaler
+// with deliberately different output, so a test can tell which one a render
+// actually used.
+type DualMarshaler int
+
+func (d DualMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(d)) + `.0`), nil
+}
+
+func (d DualMarshaler) MarshalText() ([]byte, error) {
+	return []byte("text:" + strconv.Itoa(int(d))), nil
+}
+
+type Secret struct {
+	Value string
+}
+
+func (s *Secret) ViewRender(viewName string) (interface{}, error) {
+	if viewName == "admin" {
+		return s.Value, nil
+	}
+	return nil, errors.New("secret: not allowed for view " + viewName)
+}
+
+func TestTypeMappers(test *testing.T) {
+	Convey("It renders time.Time as an RFC3339 string", test, func() {
+		type Scheduled struct {
+			At time.Time `view:"admin"`
+		}
+		at := time.Date(2026, 7, 26, 10, 0, 0, 0, time.UTC)
+		v, err := Render(&Scheduled{at}, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{"At": "2026-07-26T10:00:00Z"})
+	})
+
+	Convey("It uses encoding.TextMarshaler to render a field", test, func() {
+		type Invoice struct {
+			Total Money `view:"admin"`
+		}
+		v, err := Render(&Invoice{Total: 1050}, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{"Total": "$10.50"})
+	})
+
+	Convey("It uses json.Marshaler to render a field", test, func() {
+		type Article struct {
+			Tags Tags `view:"admin"`
+		}
+		v, err := Render(&Article{Tags: Tags{"a", "b", "c"}}, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{"Tags": map[string]interface{}{"count": float64(3)}})
+	})
+
+	Convey("It defers to a Viewer implementation", test, func() {
+		type Account struct {
+			Secret *Secret `view:"admin,support"`
+		}
+		a := &Account{Secret: &Secret{Value: "top-secret"}}
+
+		v, err := Render(a, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{"Secret": "top-secret"})
+
+		v, err = Render(a, "support")
+		So(v, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("A pointer-receiver Marshaler falls back to the Kind-based default on an unaddressable map value", test, func() {
+		type Wallet struct {
+			Balances map[string]PtrMoney `view:"admin"`
+		}
+		w := &Wallet{Balances: map[string]PtrMoney{"USD": 1050}}
+		v, err := Render(w, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{
+			"Balances": map[string]interface{}{"USD": PtrMoney(1050)},
+		})
+	})
+
+	Convey("RegisterTypeMapper overrides the default rendering of a type", test, func() {
+		type Box struct {
+			Contents Money `view:"admin"`
+		}
+		m := NewMapper(tagName, nil)
+		m.RegisterTypeMapper(reflect.TypeOf(Money(0)), func(v reflect.Value, viewName string) interface{} {
+			return int(v.Interface().(Money))
+		})
+		v, err := m.Render(&Box{Contents: 1050}, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{"Contents": 1050})
+	})
+
+	Convey("RegisterTypeMapper takes effect even after the type was already rendered once", test, func() {
+		type Invoice struct {
+			Total Money `view:"admin"`
+		}
+		m := NewMapper(tagName, nil)
+
+		_, err := m.Render(&Invoice{Total: 1050}, "admin")
+		So(err, ShouldBeNil)
+
+		m.RegisterTypeMapper(reflect.TypeOf(Money(0)), func(v reflect.Value, viewName string) interface{} {
+			return int(v.Interface().(Money))
+		})
+
+		v, err := m.Render(&Invoice{Total: 1050}, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{"Total": 1050})
+	})
+
+	Convey("json.Marshaler takes precedence over encoding.TextMarshaler, like encoding/json", test, func() {
+		type Item struct {
+			Price DualMarshaler `view:"admin"`
+		}
+		v, err := Render(&Item{Price: 7}, "admin")
+		So(err, ShouldBeNil)
+		So(v, ShouldResemble, map[string]interface{}{"Price": float64(7)})
+	})
+}
@@ -6,7 +6,6 @@ import (
 	"sync"
 )
 
-// TODO: field name converter
 // TODO: add force flag (split caching)
 
 const tagName = "view"
@@ -21,16 +20,192 @@ func (e *UnsupportedTypeError) Error() string {
 	return "struct-view: unsupported type: " + e.Type.String()
 }
 
+// defaultMapper is the Mapper used by the package-level Render function: it
+// keys views off the "view" tag and keeps Go field names as-is.
+var defaultMapper = NewMapper(tagName, nil)
+
+// Render renders src as seen through viewName, using the default field-name
+// mapping (Go field names, unchanged). It is a thin wrapper over
+// defaultMapper.Render.
 func Render(src interface{}, viewName string) (interface{}, error) {
-	m := &viewMatcher{viewName}
-	opt := &options{false, viewName, m.match}
+	return defaultMapper.Render(src, viewName)
+}
+
+// RenderWithFilter renders src as a projection of filter, using the default
+// field-name mapping. It is a thin wrapper over defaultMapper.RenderWithFilter.
+func RenderWithFilter(src interface{}, filter FieldFilter) (interface{}, error) {
+	return defaultMapper.RenderWithFilter(src, filter)
+}
+
+// Mapper renders structs into views and controls how their field names are
+// turned into output map keys.
+type Mapper struct {
+	tag        string
+	nameFunc   func(string) string
+	altTag     string
+	stringKeys bool
+	keyFunc    KeyFunc
+	onCycle    CyclePolicy
+	maxDepth   int
+
+	typeMappersMu sync.RWMutex
+	typeMappers   map[reflect.Type]func(v reflect.Value, viewName string) interface{}
+}
+
+// MapperOption configures a Mapper constructed via NewMapper.
+type MapperOption func(*Mapper)
+
+// AltTag makes the Mapper fall back to tag (e.g. "json") for a field's
+// output name when the view tag didn't set an explicit name=... override.
+// The first comma-separated part of the tag's value is used, mirroring how
+// encoding/json reads its own tag, and a "-" value is ignored.
+func AltTag(tag string) MapperOption {
+	return func(m *Mapper) { m.altTag = tag }
+}
+
+// StringKeys makes the Mapper render every map as map[string]interface{},
+// converting non-string keys with KeyFunc. This is what produces a
+// JSON-shaped result for maps with numeric or struct keys.
+func StringKeys(enable bool) MapperOption {
+	return func(m *Mapper) { m.stringKeys = enable }
+}
+
+// WithKeyFunc overrides the default KeyFunc (fmt.Sprint, honoring
+// encoding.TextMarshaler) used to stringify map keys, and implies
+// StringKeys(true).
+func WithKeyFunc(fn KeyFunc) MapperOption {
+	return func(m *Mapper) {
+		m.keyFunc = fn
+		m.stringKeys = true
+	}
+}
+
+func (m *Mapper) resolvedKeyFunc() KeyFunc {
+	if m.keyFunc != nil {
+		return m.keyFunc
+	}
+	return defaultKeyFunc
+}
+
+// NewMapper creates a Mapper that reads views from tag and, unless a field
+// sets an explicit name=... override (or AltTag resolves one), derives output
+// names from Go field names via nameFunc. nameFunc may be nil to keep Go
+// field names unchanged.
+func NewMapper(tag string, nameFunc func(string) string, opts ...MapperOption) *Mapper {
+	m := &Mapper{tag: tag, nameFunc: nameFunc}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Render renders src as seen through viewName, naming output fields per the
+// Mapper's configuration.
+func (m *Mapper) Render(src interface{}, viewName string) (interface{}, error) {
+	vm := &viewMatcher{viewName}
+	opt := &options{mapper: m, viewName: viewName, fieldMatcher: vm.match}
 	return mapInterface(src, opt)
 }
 
+// RenderWithFilter renders src as a projection of filter, naming output
+// fields per the Mapper's configuration. Unlike Render, inclusion is decided
+// field-by-field by filter rather than by a view tag, which lets callers
+// project arbitrary, request-specific subtrees (e.g. a gRPC FieldMask or a
+// GraphQL selection set).
+func (m *Mapper) RenderWithFilter(src interface{}, filter FieldFilter) (interface{}, error) {
+	opt := &options{mapper: m, filter: filter, noCache: filter != nil}
+	return mapInterface(src, opt)
+}
+
+// fieldName resolves the output map key for f: an explicit name=... override
+// wins, then AltTag (if configured), then nameFunc(f.name), then f.name as-is.
+func (m *Mapper) fieldName(f *field) string {
+	if f.hasNameOverride {
+		return f.nameOverride
+	}
+	if m.altTag != "" {
+		if raw, ok := f.structTag.Lookup(m.altTag); ok {
+			name := strings.SplitN(raw, ",", 2)[0]
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+	}
+	if m.nameFunc != nil {
+		return m.nameFunc(f.name)
+	}
+	return f.name
+}
+
+// RegisterTypeMapper makes the Mapper render values of type t with fn
+// instead of reflecting into them. fn receives the value being rendered and
+// the view name in effect, and its result is used as-is. This takes
+// precedence over Viewer, time.Time, encoding.TextMarshaler and
+// json.Marshaler support, and over the Kind-based defaults.
+//
+// It discards every type mapper already cached for m, so it is safe to call
+// at any time, including after m has already rendered values: the next
+// Render/RenderWithFilter call picks up fn instead of silently continuing to
+// use whatever was built (and cached) before this call.
+func (m *Mapper) RegisterTypeMapper(t reflect.Type, fn func(v reflect.Value, viewName string) interface{}) {
+	m.typeMappersMu.Lock()
+	if m.typeMappers == nil {
+		m.typeMappers = make(map[reflect.Type]func(reflect.Value, string) interface{})
+	}
+	m.typeMappers[t] = fn
+	m.typeMappersMu.Unlock()
+
+	m.invalidateCache()
+}
+
+// invalidateCache discards every mapperFunc cached for m across every type
+// and view. A cached struct's own fieldMappers are resolved once at build
+// time, so merely dropping t's own cache entry wouldn't be enough to pick up
+// a new registeredTypeMapper for t: anything that embeds or contains t could
+// still be holding on to a mapperFunc built before the registration.
+func (m *Mapper) invalidateCache() {
+	mapperCache.Lock()
+	for key := range mapperCache.m {
+		if key.mapper == m {
+			delete(mapperCache.m, key)
+		}
+	}
+	mapperCache.Unlock()
+}
+
+func (m *Mapper) registeredTypeMapper(t reflect.Type, viewName string) mapperFunc {
+	m.typeMappersMu.RLock()
+	fn, ok := m.typeMappers[t]
+	m.typeMappersMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	return func(v reflect.Value, rs *renderState) interface{} {
+		return fn(v, viewName)
+	}
+}
+
 type options struct {
 	noCache      bool
-	cacheTag     string
+	mapper       *Mapper
+	viewName     string
 	fieldMatcher func(f field) bool
+	filter       FieldFilter
+}
+
+// childOptions derives the options used to render a matched field's own
+// value: same mapper, scoped down to filter. It always bypasses the
+// type-mapper cache, even when filter is nil (a field fully included by its
+// parent filter): RenderWithFilter options carry no viewName, so a cached
+// entry would collide with the one a literal mapper.Render(x, "") call uses,
+// and the two can mean very different things (the latter matches fields
+// whose view tag defaults to the empty view, see parseFieldTag).
+func childOptions(opt *options, filter FieldFilter) *options {
+	return &options{
+		noCache: true,
+		mapper:  opt.mapper,
+		filter:  filter,
+	}
 }
 
 type viewMatcher struct {
@@ -44,22 +219,35 @@ func (m *viewMatcher) match(f field) bool {
 func mapInterface(src interface{}, opt *options) (i interface{}, err error) {
 	defer func() {
 		if r := recover(); r != nil {
-			if e, ok := r.(*UnsupportedTypeError); ok {
+			switch e := r.(type) {
+			case *UnsupportedTypeError:
 				err = e
-				return
+			case renderError:
+				err = e.err
+			default:
+				panic(r)
 			}
-			panic(r)
 		}
 	}()
-	i = mapValue(reflect.ValueOf(src), opt)
+	rs := newRenderState(opt.mapper)
+	i = mapValue(reflect.ValueOf(src), opt, rs)
 	return
 }
 
-func mapValue(v reflect.Value, opt *options) interface{} {
-	return getValueMapper(v, opt)(v)
+// renderError lets a mapperFunc (e.g. one backed by a Viewer or a
+// Marshaler) abort rendering with an arbitrary error, which mapInterface
+// unwraps and returns instead of the panic propagating to the caller.
+type renderError struct{ err error }
+
+func mapValue(v reflect.Value, opt *options, rs *renderState) interface{} {
+	return getValueMapper(v, opt)(v, rs)
 }
 
-type mapperFunc func(v reflect.Value) interface{}
+// mapperFunc renders v as seen through a single already-resolved view. rs is
+// per-Render call state (the cycle/depth guard); unlike opt, it must never be
+// captured by a cached mapperFunc, since the same cached func is reused
+// across many unrelated Render calls.
+type mapperFunc func(v reflect.Value, rs *renderState) interface{}
 
 func getValueMapper(v reflect.Value, opt *options) mapperFunc {
 	if !v.IsValid() {
@@ -72,17 +260,18 @@ func getValueMapper(v reflect.Value, opt *options) mapperFunc {
 	return tm
 }
 
-func invalidValueMapper(v reflect.Value) interface{} {
+func invalidValueMapper(v reflect.Value, rs *renderState) interface{} {
 	return nil
 }
 
-func identityMapper(v reflect.Value) interface{} {
+func identityMapper(v reflect.Value, rs *renderState) interface{} {
 	return v.Interface()
 }
 
 type mapperCacheKey struct {
-	t   reflect.Type
-	tag string
+	t      reflect.Type
+	mapper *Mapper
+	view   string
 }
 
 var mapperCache struct {
@@ -97,7 +286,7 @@ func getTypeMapper(t reflect.Type, opt *options) mapperFunc {
 		return newTypeMapper(t, opt)
 	}
 
-	key := mapperCacheKey{t, opt.cacheTag}
+	key := mapperCacheKey{t, opt.mapper, opt.viewName}
 	mapperCache.RLock()
 	f := mapperCache.m[key]
 	mapperCache.RUnlock()
@@ -115,9 +304,9 @@ func getTypeMapper(t reflect.Type, opt *options) mapperFunc {
 	}
 	var wg sync.WaitGroup
 	wg.Add(1)
-	mapperCache.m[key] = func(v reflect.Value) interface{} {
+	mapperCache.m[key] = func(v reflect.Value, rs *renderState) interface{} {
 		wg.Wait()
-		return f(v)
+		return f(v, rs)
 	}
 	mapperCache.Unlock()
 
@@ -133,6 +322,38 @@ func getTypeMapper(t reflect.Type, opt *options) mapperFunc {
 }
 
 func newTypeMapper(t reflect.Type, opt *options) mapperFunc {
+	// Special-cased types are checked before the Kind-based dispatch below,
+	// mirroring how encoding/json special-cases Marshaler implementations:
+	// a type with custom rendering logic should use it regardless of its
+	// underlying Kind. json.Marshaler is checked before encoding.TextMarshaler,
+	// same as encoding/json itself, so a type implementing both gets the same
+	// precedence here as it would there.
+	if fn := opt.mapper.registeredTypeMapper(t, opt.viewName); fn != nil {
+		return fn
+	}
+	if fn := newViewerMapper(t, opt); fn != nil {
+		return fn
+	}
+	if t == timeType {
+		return timeMapper
+	}
+	if fn := newJSONMarshalerMapper(t, opt); fn != nil {
+		return fn
+	}
+	if fn := newTextMarshalerMapper(t, opt); fn != nil {
+		return fn
+	}
+
+	return newKindMapper(t, opt)
+}
+
+// newKindMapper builds a mapperFunc from t's Kind alone, ignoring any
+// Viewer/time.Time/Marshaler special-casing. Besides being newTypeMapper's
+// ordinary dispatch, it is also what Viewer/TextMarshaler/JSONMarshaler
+// mappers fall back to when needsAddr is true but the value turns out to be
+// unaddressable at render time, so their pointer-receiver method can't be
+// reached.
+func newKindMapper(t reflect.Type, opt *options) mapperFunc {
 	switch t.Kind() {
 	case reflect.Invalid, reflect.Func, reflect.Chan, reflect.UnsafePointer:
 		return unsupportedTypeMapper
@@ -153,7 +374,7 @@ func newTypeMapper(t reflect.Type, opt *options) mapperFunc {
 	}
 }
 
-func unsupportedTypeMapper(v reflect.Value) interface{} {
+func unsupportedTypeMapper(v reflect.Value, rs *renderState) interface{} {
 	panic(&UnsupportedTypeError{v.Type()})
 }
 
@@ -170,11 +391,16 @@ type ptrMapper struct {
 	elemMapper mapperFunc
 }
 
-func (pm *ptrMapper) mapValue(v reflect.Value) interface{} {
+func (pm *ptrMapper) mapValue(v reflect.Value, rs *renderState) interface{} {
 	if v.IsNil() {
 		return nil
 	}
-	return pm.elemMapper(v.Elem())
+	replacement, leave, ok := rs.enter(v)
+	defer leave()
+	if !ok {
+		return replacement
+	}
+	return pm.elemMapper(v.Elem(), rs)
 }
 
 func newInterfaceMapper(_ reflect.Type, opt *options) mapperFunc {
@@ -186,11 +412,11 @@ type interfaceMapper struct {
 	opt *options
 }
 
-func (im *interfaceMapper) mapValue(v reflect.Value) interface{} {
+func (im *interfaceMapper) mapValue(v reflect.Value, rs *renderState) interface{} {
 	if v.IsNil() {
 		return nil
 	}
-	return mapValue(v.Elem(), im.opt)
+	return mapValue(v.Elem(), im.opt, rs)
 }
 
 func newSliceMapper(t reflect.Type, opt *options) mapperFunc {
@@ -206,11 +432,16 @@ type sliceMapper struct {
 	arrayMapper mapperFunc
 }
 
-func (sm *sliceMapper) mapValue(v reflect.Value) interface{} {
+func (sm *sliceMapper) mapValue(v reflect.Value, rs *renderState) interface{} {
 	if v.IsNil() {
 		return nil
 	}
-	return sm.arrayMapper(v)
+	replacement, leave, ok := rs.enter(v)
+	defer leave()
+	if !ok {
+		return replacement
+	}
+	return sm.arrayMapper(v, rs)
 }
 
 func newArrayMapper(t reflect.Type, opt *options) mapperFunc {
@@ -231,21 +462,26 @@ var (
 	voidInterfaceType     = reflect.TypeOf(voidInterfaceValuePtr).Elem()
 )
 
-func (am *arrayMapper) mapValue(v reflect.Value) interface{} {
+func (am *arrayMapper) mapValue(v reflect.Value, rs *renderState) interface{} {
 	l := v.Len()
 	result := make([]interface{}, l)
 	for i := 0; i < l; i++ {
-		result[i] = am.elemMapper(v.Index(i))
+		result[i] = am.elemMapper(v.Index(i), rs)
 	}
 	return result
 }
 
 func newMapMapper(t reflect.Type, opt *options) mapperFunc {
-	fn := getTypeMapper(t.Elem(), opt)
-	if fn == nil {
+	elemFn := getTypeMapper(t.Elem(), opt)
+
+	if opt.mapper.stringKeys {
+		return newStringKeyMapMapper(t, opt, elemFn)
+	}
+
+	if elemFn == nil {
 		return nil
 	}
-	sm := &mapMapper{reflect.MapOf(t.Key(), voidInterfaceType), fn}
+	sm := &mapMapper{reflect.MapOf(t.Key(), voidInterfaceType), elemFn}
 	return sm.mapValue
 }
 
@@ -254,11 +490,16 @@ type mapMapper struct {
 	elemMapper mapperFunc
 }
 
-func (mm *mapMapper) mapValue(v reflect.Value) interface{} {
+func (mm *mapMapper) mapValue(v reflect.Value, rs *renderState) interface{} {
+	replacement, leave, ok := rs.enter(v)
+	defer leave()
+	if !ok {
+		return replacement
+	}
 	result := reflect.MakeMap(mm.mapType)
 	keys := v.MapKeys()
 	for _, key := range keys {
-		result.SetMapIndex(key, reflect.ValueOf(mm.elemMapper(v.MapIndex(key))))
+		result.SetMapIndex(key, reflect.ValueOf(mm.elemMapper(v.MapIndex(key), rs)))
 	}
 	return result.Interface()
 }
@@ -266,19 +507,24 @@ func (mm *mapMapper) mapValue(v reflect.Value) interface{} {
 // структура не преобразуется если не совпало ни одно поле (или совпали все поля) и
 // нет необходимости преобразовывать какое-либо поле
 func newStructMapper(t reflect.Type, opt *options) mapperFunc {
-	fields := getTypeFields(t)
+	if opt.filter != nil {
+		return newFilteredStructMapper(t, opt)
+	}
+
+	fields := getTypeFields(t, opt.mapper.tag)
 	fieldMappers := make([]mapperFunc, len(fields))
+	names := make([]string, len(fields))
 	canBeIdent := true
 
 	var (
 		matchedFields  []field
+		matchedNames   []string
 		matchedMappers []mapperFunc
 	)
 
 	// make mappers
 	for i, f := range fields {
-		// TODO: fieldTypeByIndex(reflect.Type, []int) reflect.Type
-		ft := t.Field(f.index).Type
+		ft := typeByIndex(t, f.index)
 		fn := getTypeMapper(ft, opt)
 		// TODO: comment this
 		if fn == nil {
@@ -288,8 +534,22 @@ func newStructMapper(t reflect.Type, opt *options) mapperFunc {
 		}
 		fieldMappers[i] = fn
 
-		if opt.fieldMatcher(f) {
+		name := opt.mapper.fieldName(&f)
+		names[i] = name
+		if name != f.name {
+			canBeIdent = false
+		}
+		// A promoted field (one reached via an embedded struct) has no
+		// counterpart at depth 1 on t itself, so returning t unchanged would
+		// never expose it under its dotted name: the identity shortcut only
+		// holds for fields that already sit directly on t.
+		if len(f.index) > 1 {
+			canBeIdent = false
+		}
+
+		if opt.fieldMatcher == nil || opt.fieldMatcher(f) {
 			matchedFields = append(matchedFields, f)
+			matchedNames = append(matchedNames, name)
 			matchedMappers = append(matchedMappers, fn)
 		}
 	}
@@ -301,11 +561,50 @@ func newStructMapper(t reflect.Type, opt *options) mapperFunc {
 	}
 	if len(matchedFields) == 0 {
 		matchedFields = fields
+		matchedNames = names
 		matchedMappers = fieldMappers
 	}
 
 	sm := &structMapper{
 		fields:       matchedFields,
+		names:        matchedNames,
+		fieldMappers: matchedMappers,
+	}
+	return sm.mapValue
+}
+
+// newFilteredStructMapper is newStructMapper's counterpart for
+// RenderWithFilter: fields are projected by opt.filter instead of by a view
+// tag, and a field's sub FieldFilter (if any) is threaded into the mapper
+// built for that field's own value.
+func newFilteredStructMapper(t reflect.Type, opt *options) mapperFunc {
+	fields := getTypeFields(t, opt.mapper.tag)
+
+	var (
+		matchedFields  []field
+		matchedNames   []string
+		matchedMappers []mapperFunc
+	)
+	for _, f := range fields {
+		include, sub := opt.filter.Filter(strings.Split(f.name, fieldJoiner))
+		if !include {
+			continue
+		}
+
+		ft := typeByIndex(t, f.index)
+		fn := getTypeMapper(ft, childOptions(opt, sub))
+		if fn == nil {
+			fn = identityMapper
+		}
+
+		matchedFields = append(matchedFields, f)
+		matchedNames = append(matchedNames, opt.mapper.fieldName(&f))
+		matchedMappers = append(matchedMappers, fn)
+	}
+
+	sm := &structMapper{
+		fields:       matchedFields,
+		names:        matchedNames,
 		fieldMappers: matchedMappers,
 	}
 	return sm.mapValue
@@ -313,30 +612,70 @@ func newStructMapper(t reflect.Type, opt *options) mapperFunc {
 
 type structMapper struct {
 	fields       []field
+	names        []string
 	fieldMappers []mapperFunc
 }
 
-func (sm *structMapper) mapValue(v reflect.Value) interface{} {
+func (sm *structMapper) mapValue(v reflect.Value, rs *renderState) interface{} {
 	result := make(map[string]interface{})
 	for i := range sm.fields {
 		f := &sm.fields[i]
-		// TODO: fieldValueByIndex(reflect.Value, []int) reflect.Value
-		fv := v.Field(f.index)
-		val := sm.fieldMappers[i](fv)
-		result[f.name] = val
+		fv, ok := fieldByIndex(v, f.index)
+		var val interface{}
+		if ok {
+			val = sm.fieldMappers[i](fv, rs)
+		}
+		result[sm.names[i]] = val
 	}
 	return result
 }
 
+// typeByIndex walks a field index path (as produced by buildTypeFields) and
+// returns the type of the field it points to, dereferencing embedded
+// pointer-to-struct types along the way.
+func typeByIndex(t reflect.Type, index []int) reflect.Type {
+	for i, x := range index {
+		if i > 0 && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		t = t.Field(x).Type
+	}
+	return t
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but it doesn't panic when
+// it meets a nil pointer on the path to a promoted field: it reports ok=false
+// instead so the caller can render the field as nil.
+func fieldByIndex(v reflect.Value, index []int) (_ reflect.Value, ok bool) {
+	for i, x := range index {
+		if i > 0 {
+			if v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					return reflect.Value{}, false
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+type fieldCacheKey struct {
+	t   reflect.Type
+	tag string
+}
+
 var fieldCache struct {
 	sync.RWMutex
-	m map[reflect.Type][]field
+	m map[fieldCacheKey][]field
 }
 
 // getTypeFields is like buildTypeFields but uses a cache to avoid repeated work.
-func getTypeFields(t reflect.Type) []field {
+func getTypeFields(t reflect.Type, tag string) []field {
+	key := fieldCacheKey{t, tag}
 	fieldCache.RLock()
-	f, exists := fieldCache.m[t]
+	f, exists := fieldCache.m[key]
 	fieldCache.RUnlock()
 	if exists {
 		return f
@@ -344,36 +683,100 @@ func getTypeFields(t reflect.Type) []field {
 
 	// Compute fields without lock.
 	// Might duplicate effort but won't hold other computations back.
-	f = buildTypeFields(t)
+	f = buildTypeFields(t, tag)
 
 	fieldCache.Lock()
 	if fieldCache.m == nil {
-		fieldCache.m = make(map[reflect.Type][]field)
+		fieldCache.m = make(map[fieldCacheKey][]field)
 	}
-	fieldCache.m[t] = f
+	fieldCache.m[key] = f
 	fieldCache.Unlock()
 	return f
 }
 
-func buildTypeFields(t reflect.Type) (fields []field) {
+// fieldJoiner separates the enclosing type's embedding path from a promoted
+// field's own name, e.g. "Bar.B".
+const fieldJoiner = "."
+
+func buildTypeFields(t reflect.Type, tag string) (fields []field) {
+	return appendTypeFields(fields, t, tag, nil, "", map[reflect.Type]bool{t: true})
+}
+
+// appendTypeFields walks t's fields, recursing into anonymous (embedded)
+// struct fields so their fields are promoted to the enclosing type under a
+// dotted name. index is the index path of t itself within the root type,
+// and prefix is the dotted name built up from the embeddings seen so far.
+// seen holds every type already on the current embedding path (t included),
+// so a struct that (directly or indirectly) embeds itself, e.g.
+// `type Node struct { *Node; Value int }`, stops promoting once it would
+// revisit a type and keeps the recursive field as an ordinary one instead
+// of recursing forever.
+func appendTypeFields(fields []field, t reflect.Type, tag string, index []int, prefix string, seen map[reflect.Type]bool) []field {
 	for i := 0; i < t.NumField(); i++ {
 		sf := t.Field(i)
-		fields = append(fields, newField(sf))
+		idx := make([]int, len(index)+1)
+		copy(idx, index)
+		idx[len(index)] = i
+
+		// An anonymous struct field is promoted into the enclosing type
+		// unless it opts out with `view:"-"`, in which case it is kept as
+		// an ordinary (never-matching) field, same as "-" on any other
+		// field: absent whenever a sibling matches the requested view, but
+		// still present in the fallback "nothing matched" case.
+		if sf.Anonymous && sf.Tag.Get(tag) != "-" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !seen[ft] {
+				seen[ft] = true
+				fields = appendTypeFields(fields, ft, tag, idx, prefix+sf.Name+fieldJoiner, seen)
+				delete(seen, ft)
+				continue
+			}
+		}
+
+		fields = append(fields, newField(sf, tag, idx, prefix))
+	}
+	return fields
+}
+
+func newField(sf reflect.StructField, tag string, index []int, prefix string) field {
+	tagValue := sf.Tag.Get(tag)
+	views, nameOverride, hasNameOverride := parseFieldTag(tagValue)
+	return field{
+		name:            prefix + sf.Name,
+		index:           index,
+		tag:             tagValue,
+		views:           views,
+		nameOverride:    nameOverride,
+		hasNameOverride: hasNameOverride,
+		structTag:       sf.Tag,
 	}
-	return
 }
 
-func newField(sf reflect.StructField) field {
-	tag := sf.Tag.Get(tagName)
-	views := strings.Split(tag, ",")
-	return field{sf.Name, sf.Index[0], tag, views}
+// parseFieldTag splits a view tag's value into its comma-separated view list
+// and an optional ";name=..." override, e.g. "admin,support;name=user_id".
+func parseFieldTag(tagValue string) (views []string, name string, hasName bool) {
+	parts := strings.Split(tagValue, ";")
+	views = strings.Split(parts[0], ",")
+	for _, directive := range parts[1:] {
+		kv := strings.SplitN(directive, "=", 2)
+		if len(kv) == 2 && kv[0] == "name" {
+			name, hasName = kv[1], true
+		}
+	}
+	return
 }
 
 type field struct {
-	name  string
-	index int
-	tag   string
-	views []string
+	name            string
+	index           []int
+	tag             string
+	views           []string
+	nameOverride    string
+	hasNameOverride bool
+	structTag       reflect.StructTag
 }
 
 func (f *field) isMatchView(viewName string) bool {
@@ -2,6 +2,7 @@ package view
 
 import (
 	. "github.com/smartystreets/goconvey/convey"
+	"strings"
 	"testing"
 )
 
@@ -133,7 +134,123 @@ func TestRender(test *testing.T) {
 	})
 
 	Convey("Name conversion", test, func() {
+		type Account struct {
+			UserId   uint   `view:"admin" json:"id"`
+			FullName string `view:"admin;name=name"`
+			Email    string `view:"admin" json:"email,omitempty"`
+		}
+		a := &Account{UserId: 7, FullName: "Jon Doe", Email: "jon@example.com"}
+
+		Convey("A Mapper applies nameFunc to Go field names", func() {
+			m := NewMapper(tagName, strings.ToLower)
+			v, err := m.Render(a, "admin")
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, map[string]interface{}{
+				"userid": uint(7),
+				"name":   "Jon Doe",
+				"email":  "jon@example.com",
+			})
+		})
+
+		Convey("An explicit name=... tag override wins over nameFunc", func() {
+			m := NewMapper(tagName, strings.ToUpper)
+			v, err := m.Render(a, "admin")
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, map[string]interface{}{
+				"USERID": uint(7),
+				"name":   "Jon Doe",
+				"EMAIL":  "jon@example.com",
+			})
+		})
+
+		Convey("AltTag falls back to a secondary tag (e.g. json) when no override is set", func() {
+			m := NewMapper(tagName, nil, AltTag("json"))
+			v, err := m.Render(a, "admin")
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, map[string]interface{}{
+				"id":    uint(7),
+				"name":  "Jon Doe",
+				"email": "jon@example.com",
+			})
+		})
+	})
+
+	Convey("It promotes fields of embedded structs to the enclosing type", test, func() {
+		type Address struct {
+			City string `view:"admin"`
+			Zip  string
+		}
+		type Contact struct {
+			Address
+			Phone string `view:"admin"`
+		}
+
+		Convey("Promoted fields are flattened under a dotted name", func() {
+			c := &Contact{Address{"Berlin", "10115"}, "+49"}
+			v, err := Render(c, "admin")
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, map[string]interface{}{
+				"Address.City": "Berlin",
+				"Phone":        "+49",
+			})
+		})
 
+		Convey("A nil pointer on the path to a promoted field renders as nil", func() {
+			type PtrContact struct {
+				*Address
+				Phone string `view:"admin"`
+			}
+			c := &PtrContact{nil, "+49"}
+			v, err := Render(c, "admin")
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, map[string]interface{}{
+				"Address.City": nil,
+				"Phone":        "+49",
+			})
+		})
+
+		Convey("It still flattens the struct when every field, including promoted ones, matches the view", func() {
+			type FullAddress struct {
+				City string `view:"admin"`
+			}
+			type AllMatch struct {
+				FullAddress
+				Phone string `view:"admin"`
+			}
+			c := &AllMatch{FullAddress{"Berlin"}, "+49"}
+			v, err := Render(c, "admin")
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, map[string]interface{}{
+				"FullAddress.City": "Berlin",
+				"Phone":            "+49",
+			})
+		})
+
+		Convey("A struct that embeds a pointer to itself does not recurse forever", func() {
+			type Node struct {
+				*Node
+				Value int `view:"admin"`
+			}
+			n := &Node{Value: 7}
+			v, err := Render(n, "admin")
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, map[string]interface{}{
+				"Value": 7,
+			})
+		})
+
+		Convey("view:\"-\" skips an embedded struct entirely", func() {
+			type SkippedContact struct {
+				Address `view:"-"`
+				Phone   string `view:"admin"`
+			}
+			c := &SkippedContact{Address{"Berlin", "10115"}, "+49"}
+			v, err := Render(c, "admin")
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, map[string]interface{}{
+				"Phone": "+49",
+			})
+		})
 	})
 }
 